@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheMaxAge is how long a cache entry is kept before PruneStale removes
+// it, regardless of whether it's still valid per its ETag.
+const cacheMaxAge = 30 * 24 * time.Hour
+
+// DefaultCacheDir returns ~/.cache/gitpeek, falling back to a relative
+// ".gitpeek-cache" if the user's home directory can't be determined.
+func DefaultCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".gitpeek-cache"
+	}
+	return filepath.Join(home, ".cache", "gitpeek")
+}
+
+// cacheEntry is the on-disk record of a single cached response, stored
+// alongside the response body it describes.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StatusCode   int       `json:"status_code"`
+	CachedAt     time.Time `json:"cached_at"`
+}
+
+// CachingTransport is an http.RoundTripper that persists responses under
+// Dir, keyed by request URL, and revalidates them with If-None-Match /
+// If-Modified-Since instead of re-fetching full bodies on every run.
+type CachingTransport struct {
+	Dir     string
+	Refresh bool
+	Next    http.RoundTripper
+
+	mu sync.Mutex
+}
+
+// NewCachingTransport builds a CachingTransport rooted at dir, creating
+// the directory if necessary. Network requests go through
+// http.DefaultTransport, which is set as Next.
+func NewCachingTransport(dir string, refresh bool) (*CachingTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &CachingTransport{Dir: dir, Refresh: refresh, Next: http.DefaultTransport}, nil
+}
+
+func (c *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.next().RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	entry, body, ok := c.load(key)
+
+	if c.Refresh || !ok {
+		return c.fetchAndStore(req, key)
+	}
+
+	revalidate := req.Clone(req.Context())
+	if entry.ETag != "" {
+		revalidate.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		revalidate.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	resp, err := c.next().RoundTrip(revalidate)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return c.syntheticResponse(req, entry, body), nil
+	}
+
+	return c.store(req, key, resp)
+}
+
+func (c *CachingTransport) fetchAndStore(req *http.Request, key string) (*http.Response, error) {
+	resp, err := c.next().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.store(req, key, resp)
+}
+
+// store persists resp's body and cache-relevant headers to disk, then
+// returns a fresh response with an unconsumed body for the caller.
+func (c *CachingTransport) store(req *http.Request, key string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		entry := cacheEntry{
+			URL:          req.URL.String(),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			StatusCode:   resp.StatusCode,
+			CachedAt:     time.Now(),
+		}
+		c.save(key, entry, body)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+func (c *CachingTransport) syntheticResponse(req *http.Request, entry cacheEntry, body []byte) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func (c *CachingTransport) next() http.RoundTripper {
+	if c.Next != nil {
+		return c.Next
+	}
+	return http.DefaultTransport
+}
+
+func (c *CachingTransport) load(key string) (cacheEntry, []byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var entry cacheEntry
+	metaBytes, err := os.ReadFile(c.metaPath(key))
+	if err != nil {
+		return entry, nil, false
+	}
+	if err := json.Unmarshal(metaBytes, &entry); err != nil {
+		return entry, nil, false
+	}
+
+	body, err := os.ReadFile(c.bodyPath(key))
+	if err != nil {
+		return entry, nil, false
+	}
+	return entry, body, true
+}
+
+func (c *CachingTransport) save(key string, entry cacheEntry, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metaBytes, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(c.metaPath(key), metaBytes, 0o644)
+	_ = os.WriteFile(c.bodyPath(key), body, 0o644)
+}
+
+func (c *CachingTransport) metaPath(key string) string {
+	return filepath.Join(c.Dir, key+".meta.json")
+}
+
+func (c *CachingTransport) bodyPath(key string) string {
+	return filepath.Join(c.Dir, key+".body")
+}
+
+// cacheKey derives the on-disk cache key from the request URL and whatever
+// credentials were attached to it. Some forge endpoints (e.g. GitHub's
+// /user/repos) return different bodies for the same URL depending on which
+// account's token made the request, so the credentials must be folded into
+// the key - otherwise two accounts sharing a cache directory can serve each
+// other's cached responses.
+func cacheKey(req *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(req.URL.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("Authorization")))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("PRIVATE-TOKEN")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// PruneStale removes cache entries older than maxAge from dir. It's best
+// effort: a directory that doesn't exist yet, or individual unreadable
+// entries, are silently skipped.
+func PruneStale(dir string, maxAge time.Duration) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	for _, f := range entries {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		metaPath := filepath.Join(dir, f.Name())
+		data, err := os.ReadFile(metaPath)
+		if err != nil {
+			continue
+		}
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		if entry.CachedAt.Before(cutoff) {
+			key := f.Name()[:len(f.Name())-len(".meta.json")]
+			os.Remove(metaPath)
+			os.Remove(filepath.Join(dir, key+".body"))
+		}
+	}
+	return nil
+}
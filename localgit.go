@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localLogFormat separates commit fields with the ASCII unit separator so
+// commit messages containing the usual punctuation can't be confused
+// with a field boundary.
+const localLogFormat = "%H\x1f%an\x1f%ae\x1f%aI\x1f%s"
+
+// FindLocalRepos recursively scans root for directories containing a
+// .git entry, stopping descent as soon as one is found so nested
+// submodules aren't double-counted.
+func FindLocalRepos(root string) ([]string, error) {
+	var repos []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if _, statErr := os.Stat(filepath.Join(path, ".git")); statErr == nil {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return repos, err
+}
+
+// localDiffstat is the additions/deletions for a single commit, keyed by
+// SHA, as parsed out of `git log --numstat` output.
+type localDiffstat struct {
+	Additions int
+	Deletions int
+}
+
+// LocalCommitsSince runs `git log --author=<author> --since=<since>
+// --numstat` against the repo at repoPath and maps the result onto the
+// shared Commit struct, along with a per-commit diffstat.
+func LocalCommitsSince(repoPath, author string, since time.Time) ([]Commit, map[string]localDiffstat, error) {
+	cmd := exec.Command("git", "-C", repoPath, "log",
+		"--author="+author,
+		"--since="+since.Format(time.RFC3339),
+		"--numstat",
+		"--pretty=format:"+localLogFormat,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("git log in %s: %w", repoPath, err)
+	}
+
+	var commits []Commit
+	stats := make(map[string]localDiffstat)
+	currentSHA := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if strings.Contains(line, "\x1f") {
+			fields := strings.Split(line, "\x1f")
+			if len(fields) != 5 {
+				continue
+			}
+			date, _ := time.Parse(time.RFC3339, fields[3])
+			commit := Commit{SHA: fields[0]}
+			commit.Commit.Author.Name = fields[1]
+			commit.Commit.Author.Email = fields[2]
+			commit.Commit.Author.Date = date
+			commit.Commit.Message = fields[4]
+			commits = append(commits, commit)
+			currentSHA = fields[0]
+			continue
+		}
+
+		// A --numstat line: "<added>\t<deleted>\t<path>". Binary files
+		// report "-" for both counts, which Atoi leaves at zero.
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 2 || currentSHA == "" {
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0])
+		deleted, _ := strconv.Atoi(parts[1])
+		entry := stats[currentSHA]
+		entry.Additions += added
+		entry.Deletions += deleted
+		stats[currentSHA] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return commits, stats, nil
+}
+
+// fetchLocalStats scans root for local clones and aggregates today/this-
+// week commit stats from each one via `git log`, skipping any commit
+// whose SHA is already in seenSHA (main fills it from fetchRemoteStats's
+// results so a commit that's been pushed isn't counted twice under
+// -mode=both).
+func fetchLocalStats(root, author string, todayStart, todayEnd, weekStart time.Time, seenSHA map[string]bool) (aggregatedStats, error) {
+	var result aggregatedStats
+
+	repoPaths, err := FindLocalRepos(root)
+	if err != nil {
+		return result, fmt.Errorf("scanning %s: %w", root, err)
+	}
+	fmt.Printf("Scanning %d local repos under %s...\n", len(repoPaths), root)
+
+	for _, path := range repoPaths {
+		commits, diffstats, err := LocalCommitsSince(path, author, weekStart)
+		if err != nil {
+			continue
+		}
+
+		var weekCommits, todayCommits []Commit
+		for _, c := range commits {
+			if seenSHA[c.SHA] {
+				continue
+			}
+			seenSHA[c.SHA] = true
+
+			weekCommits = append(weekCommits, c)
+			if !c.Commit.Author.Date.Before(todayStart) && c.Commit.Author.Date.Before(todayEnd) {
+				todayCommits = append(todayCommits, c)
+			}
+
+			diff := diffstats[c.SHA]
+			result.additions += diff.Additions
+			result.deletions += diff.Deletions
+		}
+
+		name := filepath.Base(path)
+		if len(todayCommits) > 0 {
+			result.today = append(result.today, CommitStats{Repository: name, Count: len(todayCommits), Commits: todayCommits})
+			result.todayTotal += len(todayCommits)
+		}
+		if len(weekCommits) > 0 {
+			result.week = append(result.week, CommitStats{Repository: name, Count: len(weekCommits), Commits: weekCommits})
+			result.weekTotal += len(weekCommits)
+		}
+	}
+
+	return result, nil
+}
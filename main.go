@@ -1,14 +1,10 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"net/http"
+	"math"
 	"os"
-	"sort"
-	"strings"
 	"time"
 )
 
@@ -19,6 +15,7 @@ type Repository struct {
 
 type Commit struct {
 	SHA    string `json:"sha"`
+	URL    string `json:"html_url,omitempty"`
 	Commit struct {
 		Author struct {
 			Name  string    `json:"name"`
@@ -37,87 +34,40 @@ type CommitDetail struct {
 }
 
 type CommitStats struct {
-	Repository string
-	Count      int
-	Commits    []Commit
+	Repository string   `json:"repository"`
+	Count      int      `json:"count"`
+	Commits    []Commit `json:"commits"`
 }
 
-type GitHubClient struct {
-	Token    string
-	Username string
-	Client   *http.Client
+// aggregatedStats is what a single source (remote API or local clones)
+// contributes to the final report, before main merges sources together.
+type aggregatedStats struct {
+	today      []CommitStats
+	week       []CommitStats
+	todayTotal int
+	weekTotal  int
+	additions  int
+	deletions  int
 }
 
-func NewGitHubClient(token, username string) *GitHubClient {
-	return &GitHubClient{
-		Token:    token,
-		Username: username,
-		Client:   &http.Client{Timeout: 30 * time.Second},
-	}
+// repoCommits is the per-repo result of fetching commits concurrently.
+type repoCommits struct {
+	repo    Repository
+	commits []Commit
+	err     error
 }
 
-func (g *GitHubClient) makeRequest(url string) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "token "+g.Token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := g.Client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
-	}
-
-	return io.ReadAll(resp.Body)
-}
-
-func (g *GitHubClient) GetUserRepositories() ([]Repository, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s/repos?per_page=100&sort=updated", g.Username)
-	body, err := g.makeRequest(url)
-	if err != nil {
-		return nil, err
-	}
-
-	var repos []Repository
-	if err := json.Unmarshal(body, &repos); err != nil {
-		return nil, err
-	}
-	return repos, nil
-}
-
-func (g *GitHubClient) GetCommitsForRepo(repo Repository, since time.Time) ([]Commit, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/commits?author=%s&since=%s&per_page=100",
-		repo.FullName, g.Username, since.Format(time.RFC3339))
-	body, err := g.makeRequest(url)
-	if err != nil {
-		return nil, err
-	}
-
-	var commits []Commit
-	if err := json.Unmarshal(body, &commits); err != nil {
-		return nil, err
-	}
-	return commits, nil
+// commitStatJob identifies a single commit to fetch additions/deletions
+// for, dispatched to the worker pool alongside its owning repo.
+type commitStatJob struct {
+	repo Repository
+	sha  string
 }
 
-func (g *GitHubClient) GetCommitStats(repoFullName, sha string) (int, int, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/commits/%s", repoFullName, sha)
-	body, err := g.makeRequest(url)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	var detail CommitDetail
-	if err := json.Unmarshal(body, &detail); err != nil {
-		return 0, 0, err
-	}
-	return detail.Stats.Additions, detail.Stats.Deletions, nil
+type commitStatResult struct {
+	additions int
+	deletions int
+	err       error
 }
 
 func getTimeRanges() (time.Time, time.Time, time.Time, time.Time) {
@@ -147,47 +97,131 @@ func filterCommitsByTimeRange(commits []Commit, start, end time.Time) []Commit {
 	return filtered
 }
 
-// printCommitStats displays commit statistics in a formatted way
-func printCommitStats(title string, stats []CommitStats, totalCommits int) {
-	fmt.Printf("\n=== %s ===\n", title)
-	fmt.Printf("Total commits: %d\n", totalCommits)
-	if totalCommits == 0 {
-		fmt.Println("No commits found for this period.")
-		return
+// fetchRemoteStats builds a Forge client for the given backend and
+// aggregates today/this-week commit stats from it, preferring GitHub's
+// contributor-stats endpoint over a per-commit fan-out where possible.
+func fetchRemoteStats(forgeKind, baseURL, token, username, cacheDir string, refresh, includeOrgs bool, concurrency int, todayStart, todayEnd, weekStart, weekEnd time.Time) (aggregatedStats, error) {
+	var result aggregatedStats
+
+	if err := PruneStale(cacheDir, cacheMaxAge); err != nil {
+		fmt.Printf("Warning: failed to prune cache: %v\n", err)
+	}
+	transport, err := NewCachingTransport(cacheDir, refresh)
+	if err != nil {
+		return result, err
 	}
-	fmt.Println("\nBy repository:")
 
-	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].Count > stats[j].Count
+	client, err := NewForge(forgeKind, baseURL, token, username, transport, includeOrgs)
+	if err != nil {
+		return result, err
+	}
+	fmt.Printf("Fetching commit statistics for %s...\n", username)
+
+	repos, err := client.ListRepositories()
+	if err != nil {
+		return result, fmt.Errorf("fetching repositories: %w", err)
+	}
+	fmt.Printf("Found %d repositories to check.\n", len(repos))
+
+	fmt.Printf("Fetching commits from %d repositories (concurrency=%d)...\n", len(repos), concurrency)
+	repoResults := parallelMap(repos, concurrency, func(repo Repository) repoCommits {
+		commits, err := client.ListCommitsSince(repo, weekStart)
+		return repoCommits{repo: repo, commits: commits, err: err}
 	})
 
-	for _, stat := range stats {
-		if stat.Count > 0 {
-			fmt.Printf("  %s: %d commits\n", stat.Repository, stat.Count)
-			maxShow := 3
-			if len(stat.Commits) < maxShow {
-				maxShow = len(stat.Commits)
-			}
-			for i := 0; i < maxShow; i++ {
-				commit := stat.Commits[i]
-				message := strings.Split(commit.Commit.Message, "\n")[0]
-				if len(message) > 60 {
-					message = message[:57] + "..."
+	var statJobs []commitStatJob
+	// GitHub exposes a single contributor-stats call that covers a whole
+	// repo; prefer it over a per-commit fan-out when it's available.
+	gh, isGitHub := client.(*GitHubForge)
+
+	for _, r := range repoResults {
+		if r.err != nil {
+			continue
+		}
+
+		todayCommits := filterCommitsByTimeRange(r.commits, todayStart, todayEnd)
+		if len(todayCommits) > 0 {
+			result.today = append(result.today, CommitStats{Repository: r.repo.Name, Count: len(todayCommits), Commits: todayCommits})
+			result.todayTotal += len(todayCommits)
+		}
+
+		if len(r.commits) == 0 {
+			continue
+		}
+
+		result.week = append(result.week, CommitStats{Repository: r.repo.Name, Count: len(r.commits), Commits: r.commits})
+		result.weekTotal += len(r.commits)
+
+		if isGitHub {
+			if weekly, err := gh.GetContributorWeeklyStats(r.repo); err == nil && len(weekly) > 0 {
+				for _, w := range weekly {
+					// GitHub buckets weekly stats into Sunday-aligned
+					// 7-day windows, which don't line up with our
+					// Monday-aligned [weekStart, weekEnd) report window, so a
+					// single GitHub week's totals can't just be added in on
+					// any overlap (that double-counts the shared days).
+					// Instead prorate each bucket's additions/deletions by
+					// the fraction of the bucket that actually falls inside
+					// our report window, assuming an even spread of changes
+					// across the bucket's 7 days.
+					githubWeekEnd := w.Week.Add(7 * 24 * time.Hour)
+					overlapStart := w.Week
+					if weekStart.After(overlapStart) {
+						overlapStart = weekStart
+					}
+					overlapEnd := githubWeekEnd
+					if weekEnd.Before(overlapEnd) {
+						overlapEnd = weekEnd
+					}
+					overlap := overlapEnd.Sub(overlapStart)
+					if overlap <= 0 {
+						continue
+					}
+					frac := float64(overlap) / float64(7*24*time.Hour)
+					result.additions += int(math.Round(float64(w.Additions) * frac))
+					result.deletions += int(math.Round(float64(w.Deletions) * frac))
 				}
-				fmt.Printf("    - %s\n", message)
-			}
-			if len(stat.Commits) > maxShow {
-				fmt.Printf("    ... and %d more commits\n", len(stat.Commits)-maxShow)
+				continue
 			}
 		}
+
+		for _, commit := range r.commits {
+			statJobs = append(statJobs, commitStatJob{repo: r.repo, sha: commit.SHA})
+		}
 	}
+
+	fmt.Printf("Fetching stats for %d commits (concurrency=%d)...\n", len(statJobs), concurrency)
+	statResults := parallelMap(statJobs, concurrency, func(job commitStatJob) commitStatResult {
+		additions, deletions, err := client.CommitStats(job.repo, job.sha)
+		return commitStatResult{additions: additions, deletions: deletions, err: err}
+	})
+
+	for _, s := range statResults {
+		if s.err != nil {
+			continue
+		}
+		result.additions += s.additions
+		result.deletions += s.deletions
+	}
+
+	return result, nil
 }
 
 func main() {
 	var (
-		token    = flag.String("token", "", "GitHub personal access token")
-		username = flag.String("username", "", "GitHub username")
-		help     = flag.Bool("help", false, "Show help message")
+		token       = flag.String("token", "", "Access token for the configured forge")
+		username    = flag.String("username", "", "Username to report commit activity for")
+		forge       = flag.String("forge", "", "Forge backend: github, gitlab, gitea, gerrit (default: auto-detect from -base-url, else github)")
+		baseURL     = flag.String("base-url", "", "Base URL of the forge API (for self-hosted GitLab/Gitea/Gerrit instances)")
+		cacheDir    = flag.String("cache-dir", DefaultCacheDir(), "Directory for cached HTTP responses")
+		refresh     = flag.Bool("refresh", false, "Bypass the on-disk cache and force fresh requests")
+		concurrency = flag.Int("concurrency", 8, "Number of repos/commits to fetch in parallel")
+		mode        = flag.String("mode", "remote", "Where to look for commits: remote, local, or both")
+		reposDir    = flag.String("repos", "", "Directory to scan recursively for local .git repos (required for -mode=local/both)")
+		format      = flag.String("format", "text", "Output format: text, json, markdown, html, prom")
+		serve       = flag.String("serve", "", "Serve the report at this address (e.g. :9090) instead of printing once and exiting")
+		includeOrgs = flag.Bool("include-orgs", false, "Fetch org/private repos too (GitHub only; uses /user/repos instead of /users/{u}/repos)")
+		help        = flag.Bool("help", false, "Show help message")
 	)
 	flag.Parse()
 
@@ -198,8 +232,11 @@ func main() {
 		*username = os.Getenv("GITHUB_USERNAME")
 	}
 
-	if *help || *token == "" || *username == "" {
-		fmt.Println("GitHub Commit Tracker")
+	wantRemote := *mode == "remote" || *mode == "both"
+	wantLocal := *mode == "local" || *mode == "both"
+
+	if *help || *username == "" || (wantRemote && *token == "") || (wantLocal && *reposDir == "") {
+		fmt.Println("gitpeek - commit activity tracker")
 		fmt.Println("Usage: go run main.go -token <your_token> -username <your_username>")
 		fmt.Println("Alternatively, set GITHUB_TOKEN and GITHUB_USERNAME environment variables.")
 		fmt.Println("\nFlags:")
@@ -207,64 +244,74 @@ func main() {
 		return
 	}
 
-	client := NewGitHubClient(*token, *username)
-	fmt.Printf("Fetching commit statistics for %s...\n", *username)
-
-	repos, err := client.GetUserRepositories()
+	renderer, err := NewRenderer(*format)
 	if err != nil {
-		fmt.Printf("Error fetching repositories: %v\n", err)
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("Found %d repositories to check.\n", len(repos))
 
 	todayStart, todayEnd, weekStart, weekEnd := getTimeRanges()
+
 	var todayStats, weekStats []CommitStats
-	var todayTotal, weekTotal, weekAdditions, weekDeletions int
+	var todayTotal, weekTotal int
+	var weekAdditions, weekDeletions int
+	seenSHA := make(map[string]bool)
 
-	for i, repo := range repos {
-		fmt.Printf("\rProcessing repository %d/%d: %s      ", i+1, len(repos), repo.Name)
-		commits, err := client.GetCommitsForRepo(repo, weekStart)
+	if wantRemote {
+		stats, err := fetchRemoteStats(*forge, *baseURL, *token, *username, *cacheDir, *refresh, *includeOrgs, *concurrency, todayStart, todayEnd, weekStart, weekEnd)
 		if err != nil {
-			continue
+			fmt.Printf("Error fetching remote stats: %v\n", err)
+			os.Exit(1)
 		}
-
-		todayCommits := filterCommitsByTimeRange(commits, todayStart, todayEnd)
-		if len(todayCommits) > 0 {
-			todayStats = append(todayStats, CommitStats{
-				Repository: repo.Name,
-				Count:      len(todayCommits),
-				Commits:    todayCommits,
-			})
-			todayTotal += len(todayCommits)
+		todayStats = append(todayStats, stats.today...)
+		weekStats = append(weekStats, stats.week...)
+		todayTotal += stats.todayTotal
+		weekTotal += stats.weekTotal
+		weekAdditions += stats.additions
+		weekDeletions += stats.deletions
+		for _, stat := range stats.week {
+			for _, commit := range stat.Commits {
+				seenSHA[commit.SHA] = true
+			}
 		}
+	}
 
-		if len(commits) > 0 {
-			weekStats = append(weekStats, CommitStats{
-				Repository: repo.Name,
-				Count:      len(commits),
-				Commits:    commits,
-			})
-			weekTotal += len(commits)
-
-			for _, commit := range commits {
-				adds, dels, err := client.GetCommitStats(repo.FullName, commit.SHA)
-				if err != nil {
-					continue
-				}
-				weekAdditions += adds
-				weekDeletions += dels
-			}
+	if wantLocal {
+		stats, err := fetchLocalStats(*reposDir, *username, todayStart, todayEnd, weekStart, seenSHA)
+		if err != nil {
+			fmt.Printf("Error fetching local stats: %v\n", err)
+			os.Exit(1)
 		}
+		todayStats = append(todayStats, stats.today...)
+		weekStats = append(weekStats, stats.week...)
+		todayTotal += stats.todayTotal
+		weekTotal += stats.weekTotal
+		weekAdditions += stats.additions
+		weekDeletions += stats.deletions
 	}
 
-	fmt.Println("\n\nProcessing complete.")
+	fmt.Println("Processing complete.")
+
+	report := Report{
+		Username:      *username,
+		Today:         todayStats,
+		TodayTotal:    todayTotal,
+		Week:          weekStats,
+		WeekTotal:     weekTotal,
+		WeekAdditions: weekAdditions,
+		WeekDeletions: weekDeletions,
+		TodayStart:    todayStart,
+		WeekStart:     weekStart,
+		WeekEnd:       weekEnd,
+	}
 
-	printCommitStats("TODAY'S COMMITS", todayStats, todayTotal)
-	printCommitStats("THIS WEEK'S COMMITS", weekStats, weekTotal)
+	if *serve != "" {
+		serveReport(*serve, renderer, report)
+		return
+	}
 
-	fmt.Printf("\nLines of code added this week: %d\n", weekAdditions)
-	fmt.Printf("\nLines of code deleted this week: %d\n", weekDeletions)
-	fmt.Printf("\nTime period (Today): %s\n", todayStart.Format("Jan 2, 2006"))
-	fmt.Printf("Time period (This Week): %s - %s\n", weekStart.Format("Jan 2"), weekEnd.Add(-time.Second).Format("Jan 2, 2006"))
+	if err := renderer.Render(os.Stdout, report); err != nil {
+		fmt.Printf("Error rendering report: %v\n", err)
+		os.Exit(1)
+	}
 }
-
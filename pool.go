@@ -0,0 +1,36 @@
+package main
+
+import "sync"
+
+// parallelMap applies fn to each item in items using up to concurrency
+// workers, preserving input order in the returned slice.
+func parallelMap[T, R any](items []T, concurrency int, fn func(T) R) []R {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(items) {
+		concurrency = len(items)
+	}
+
+	results := make([]R, len(items))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = fn(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
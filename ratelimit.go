@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a simple rate limiter that allows burst requests up to
+// its capacity and refills at a fixed rate. It keeps gitpeek's
+// concurrent fetchers within a forge's request budget even when the
+// worker pool outpaces the forge's own throttling.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newTokenBucket(capacity, ratePerSecond float64) *tokenBucket {
+	return &tokenBucket{tokens: capacity, capacity: capacity, rate: ratePerSecond, last: time.Now()}
+}
+
+// Wait blocks until a token is available, then consumes it.
+func (b *tokenBucket) Wait() {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return
+		}
+
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+// waitForRateLimit inspects GitHub's rate-limit headers and, if the
+// budget is exhausted, sleeps until the reset time before the caller
+// retries. It also honors a plain Retry-After header for secondary
+// rate limits and abuse detection responses.
+func waitForRateLimit(resp *http.Response) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			time.Sleep(time.Duration(seconds) * time.Second)
+			return
+		}
+	}
+
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	reset := resp.Header.Get("X-RateLimit-Reset")
+	if remaining != "0" || reset == "" {
+		return
+	}
+
+	resetUnix, err := strconv.ParseInt(reset, 10, 64)
+	if err != nil {
+		return
+	}
+
+	wait := time.Until(time.Unix(resetUnix, 0))
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report is everything a Renderer needs to produce a complete
+// commit-activity report for one run.
+type Report struct {
+	Username      string
+	Today         []CommitStats
+	TodayTotal    int
+	Week          []CommitStats
+	WeekTotal     int
+	WeekAdditions int
+	WeekDeletions int
+	TodayStart    time.Time
+	WeekStart     time.Time
+	WeekEnd       time.Time
+}
+
+// Renderer formats a Report for some consumer: a terminal, a standup
+// doc, a static site, or a Prometheus scraper.
+type Renderer interface {
+	// Render writes the formatted report to w.
+	Render(w io.Writer, report Report) error
+	// ContentType is the MIME type to use when serving this renderer's
+	// output over HTTP.
+	ContentType() string
+}
+
+// NewRenderer returns the Renderer registered for format, one of "text",
+// "json", "markdown", "html", or "prom".
+func NewRenderer(format string) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case "", "text":
+		return TextRenderer{}, nil
+	case "json":
+		return JSONRenderer{}, nil
+	case "markdown", "md":
+		return MarkdownRenderer{}, nil
+	case "html":
+		return HTMLRenderer{}, nil
+	case "prom", "prometheus":
+		return PromRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown -format %q", format)
+	}
+}
+
+// sortedByCount returns a copy of stats sorted by commit count
+// descending, so renderers don't have to each reimplement it.
+func sortedByCount(stats []CommitStats) []CommitStats {
+	sorted := make([]CommitStats, len(stats))
+	copy(sorted, stats)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Count > sorted[j].Count
+	})
+	return sorted
+}
+
+// TextRenderer reproduces gitpeek's original terminal output.
+type TextRenderer struct{}
+
+func (TextRenderer) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (TextRenderer) Render(w io.Writer, report Report) error {
+	renderTextSection(w, "TODAY'S COMMITS", report.Today, report.TodayTotal)
+	renderTextSection(w, "THIS WEEK'S COMMITS", report.Week, report.WeekTotal)
+
+	fmt.Fprintf(w, "\nLines of code added this week: %d\n", report.WeekAdditions)
+	fmt.Fprintf(w, "\nLines of code deleted this week: %d\n", report.WeekDeletions)
+	fmt.Fprintf(w, "\nTime period (Today): %s\n", report.TodayStart.Format("Jan 2, 2006"))
+	fmt.Fprintf(w, "Time period (This Week): %s - %s\n",
+		report.WeekStart.Format("Jan 2"), report.WeekEnd.Add(-time.Second).Format("Jan 2, 2006"))
+	return nil
+}
+
+func renderTextSection(w io.Writer, title string, stats []CommitStats, total int) {
+	fmt.Fprintf(w, "\n=== %s ===\n", title)
+	fmt.Fprintf(w, "Total commits: %d\n", total)
+	if total == 0 {
+		fmt.Fprintln(w, "No commits found for this period.")
+		return
+	}
+	fmt.Fprintln(w, "\nBy repository:")
+
+	for _, stat := range sortedByCount(stats) {
+		if stat.Count == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  %s: %d commits\n", stat.Repository, stat.Count)
+		maxShow := 3
+		if len(stat.Commits) < maxShow {
+			maxShow = len(stat.Commits)
+		}
+		for i := 0; i < maxShow; i++ {
+			message := firstLine(stat.Commits[i].Commit.Message)
+			fmt.Fprintf(w, "    - %s\n", message)
+		}
+		if len(stat.Commits) > maxShow {
+			fmt.Fprintf(w, "    ... and %d more commits\n", len(stat.Commits)-maxShow)
+		}
+	}
+}
+
+func firstLine(message string) string {
+	line := strings.Split(message, "\n")[0]
+	if len(line) > 60 {
+		line = line[:57] + "..."
+	}
+	return line
+}
+
+// JSONRenderer emits the full Report as JSON for scripting.
+type JSONRenderer struct{}
+
+func (JSONRenderer) ContentType() string { return "application/json; charset=utf-8" }
+
+func (JSONRenderer) Render(w io.Writer, report Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// MarkdownRenderer produces a report suitable for pasting into a standup
+// doc, grouped by repository with commit links where available.
+type MarkdownRenderer struct{}
+
+func (MarkdownRenderer) ContentType() string { return "text/markdown; charset=utf-8" }
+
+func (MarkdownRenderer) Render(w io.Writer, report Report) error {
+	fmt.Fprintf(w, "# Commit activity for %s\n\n", report.Username)
+
+	renderMarkdownSection(w, "Today", report.Today, report.TodayTotal)
+	renderMarkdownSection(w, fmt.Sprintf("This week (%s - %s)",
+		report.WeekStart.Format("Jan 2"), report.WeekEnd.Add(-time.Second).Format("Jan 2, 2006")),
+		report.Week, report.WeekTotal)
+
+	fmt.Fprintf(w, "**Lines added:** %d  **Lines deleted:** %d\n", report.WeekAdditions, report.WeekDeletions)
+	return nil
+}
+
+func renderMarkdownSection(w io.Writer, title string, stats []CommitStats, total int) {
+	fmt.Fprintf(w, "## %s\n\n", title)
+	if total == 0 {
+		fmt.Fprintf(w, "No commits found for this period.\n\n")
+		return
+	}
+
+	for _, stat := range sortedByCount(stats) {
+		if stat.Count == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "- **%s**: %d commits\n", stat.Repository, stat.Count)
+		for _, commit := range stat.Commits {
+			fmt.Fprintf(w, "  - %s %s\n", commitLink(commit), firstLine(commit.Commit.Message))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func commitLink(commit Commit) string {
+	sha := commit.SHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	if commit.URL != "" {
+		return fmt.Sprintf("[`%s`](%s)", sha, commit.URL)
+	}
+	return fmt.Sprintf("`%s`", sha)
+}
+
+// HTMLRenderer produces a standalone HTML report suitable for serving
+// from a static site.
+type HTMLRenderer struct{}
+
+func (HTMLRenderer) ContentType() string { return "text/html; charset=utf-8" }
+
+func (HTMLRenderer) Render(w io.Writer, report Report) error {
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>Commit activity for %s</title></head><body>\n",
+		html.EscapeString(report.Username))
+	fmt.Fprintf(w, "<h1>Commit activity for %s</h1>\n", html.EscapeString(report.Username))
+
+	renderHTMLSection(w, "Today", report.Today, report.TodayTotal)
+	renderHTMLSection(w, "This week", report.Week, report.WeekTotal)
+
+	fmt.Fprintf(w, "<p><strong>Lines added:</strong> %d &nbsp; <strong>Lines deleted:</strong> %d</p>\n",
+		report.WeekAdditions, report.WeekDeletions)
+	fmt.Fprintln(w, "</body></html>")
+	return nil
+}
+
+func renderHTMLSection(w io.Writer, title string, stats []CommitStats, total int) {
+	fmt.Fprintf(w, "<h2>%s</h2>\n", html.EscapeString(title))
+	if total == 0 {
+		fmt.Fprintln(w, "<p>No commits found for this period.</p>")
+		return
+	}
+
+	for _, stat := range sortedByCount(stats) {
+		if stat.Count == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "<h3>%s (%d commits)</h3>\n<ul>\n", html.EscapeString(stat.Repository), stat.Count)
+		for _, commit := range stat.Commits {
+			sha := commit.SHA
+			if len(sha) > 7 {
+				sha = sha[:7]
+			}
+			message := html.EscapeString(firstLine(commit.Commit.Message))
+			if commit.URL != "" {
+				fmt.Fprintf(w, "  <li><a href=\"%s\"><code>%s</code></a> %s</li>\n", html.EscapeString(commit.URL), sha, message)
+			} else {
+				fmt.Fprintf(w, "  <li><code>%s</code> %s</li>\n", sha, message)
+			}
+		}
+		fmt.Fprintln(w, "</ul>")
+	}
+}
+
+// PromRenderer exposes the report as Prometheus text-format gauges, so
+// gitpeek can double as a scrape target when run with -serve.
+type PromRenderer struct{}
+
+func (PromRenderer) ContentType() string { return "text/plain; version=0.0.4; charset=utf-8" }
+
+func (PromRenderer) Render(w io.Writer, report Report) error {
+	fmt.Fprintln(w, "# HELP gitpeek_commits_total Number of commits in the period, by repo.")
+	fmt.Fprintln(w, "# TYPE gitpeek_commits_total gauge")
+	for _, stat := range report.Today {
+		fmt.Fprintf(w, "gitpeek_commits_total{repo=%q,period=\"today\"} %d\n", stat.Repository, stat.Count)
+	}
+	for _, stat := range report.Week {
+		fmt.Fprintf(w, "gitpeek_commits_total{repo=%q,period=\"week\"} %d\n", stat.Repository, stat.Count)
+	}
+
+	fmt.Fprintln(w, "# HELP gitpeek_lines_added_total Lines added this week.")
+	fmt.Fprintln(w, "# TYPE gitpeek_lines_added_total gauge")
+	fmt.Fprintf(w, "gitpeek_lines_added_total %d\n", report.WeekAdditions)
+
+	fmt.Fprintln(w, "# HELP gitpeek_lines_deleted_total Lines deleted this week.")
+	fmt.Fprintln(w, "# TYPE gitpeek_lines_deleted_total gauge")
+	fmt.Fprintf(w, "gitpeek_lines_deleted_total %d\n", report.WeekDeletions)
+	return nil
+}
+
+// serveReport serves report at addr/metrics until the process is killed,
+// rendering it with renderer on each request. The report is computed once
+// at startup; restart gitpeek to refresh it.
+func serveReport(addr string, renderer Renderer, report Report) {
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", renderer.ContentType())
+		if err := renderer.Render(w, report); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	fmt.Printf("Serving report on %s/metrics (format=%s)\n", addr, renderer.ContentType())
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		fmt.Printf("Error serving report: %v\n", err)
+	}
+}
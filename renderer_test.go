@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/")
+
+// fixtureReport returns a deterministic Report covering the cases
+// renderers need to handle: multiple repos, multiple commits, a commit
+// with a multi-line message, and a commit with no URL. Neither section is
+// empty.
+func fixtureReport() Report {
+	widgetCommit := Commit{SHA: "abc123def4567890abc123def4567890abc123d", URL: "https://github.com/octocat/widgets/commit/abc123def4567890abc123def4567890abc123d"}
+	widgetCommit.Commit.Author.Name = "Mona Octocat"
+	widgetCommit.Commit.Author.Email = "mona@github.com"
+	widgetCommit.Commit.Author.Date = time.Date(2026, 7, 20, 9, 30, 0, 0, time.UTC)
+	widgetCommit.Commit.Message = "Add sprocket support\n\nLonger explanation in the body."
+
+	gizmoCommit := Commit{SHA: "def4567890abc123def4567890abc123def4567"}
+	gizmoCommit.Commit.Author.Name = "Mona Octocat"
+	gizmoCommit.Commit.Author.Email = "mona@github.com"
+	gizmoCommit.Commit.Author.Date = time.Date(2026, 7, 20, 14, 0, 0, 0, time.UTC)
+	gizmoCommit.Commit.Message = "Fix gizmo off-by-one"
+
+	return Report{
+		Username: "octocat",
+		Today: []CommitStats{
+			{Repository: "widgets", Count: 1, Commits: []Commit{widgetCommit}},
+		},
+		TodayTotal: 1,
+		Week: []CommitStats{
+			{Repository: "widgets", Count: 1, Commits: []Commit{widgetCommit}},
+			{Repository: "gizmos", Count: 1, Commits: []Commit{gizmoCommit}},
+		},
+		WeekTotal:     2,
+		WeekAdditions: 42,
+		WeekDeletions: 7,
+		TodayStart:    time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC),
+		WeekStart:     time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC),
+		WeekEnd:       time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// testGolden renders report with renderer and compares it against
+// testdata/<name>.golden, rewriting the file first when -update is set.
+func testGolden(t *testing.T, name string, renderer Renderer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, fixtureReport()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	goldenPath := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("%s output does not match golden file\n--- got ---\n%s\n--- want ---\n%s", name, buf.String(), want)
+	}
+}
+
+func TestTextRendererGolden(t *testing.T) {
+	testGolden(t, "report.text", TextRenderer{})
+}
+
+func TestJSONRendererGolden(t *testing.T) {
+	testGolden(t, "report.json", JSONRenderer{})
+}
+
+func TestMarkdownRendererGolden(t *testing.T) {
+	testGolden(t, "report.markdown", MarkdownRenderer{})
+}
+
+func TestHTMLRendererGolden(t *testing.T) {
+	testGolden(t, "report.html", HTMLRenderer{})
+}
+
+func TestPromRendererGolden(t *testing.T) {
+	testGolden(t, "report.prom", PromRenderer{})
+}